@@ -0,0 +1,49 @@
+package remoteread
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+const (
+	maxRetryAttempts = 10
+	baseRetryDelay   = time.Second
+	maxRetryDelay    = time.Minute
+)
+
+// backoff implements exponential backoff with full jitter, capped at
+// maxRetryDelay, up to maxRetryAttempts.
+type backoff struct {
+	attempt int
+}
+
+func newBackoff() *backoff {
+	return &backoff{}
+}
+
+// next returns how long to wait before the next attempt, or ok=false once
+// maxRetryAttempts has been exhausted.
+func (b *backoff) next() (d time.Duration, ok bool) {
+	if b.attempt >= maxRetryAttempts {
+		return 0, false
+	}
+	ceil := baseRetryDelay << uint(b.attempt)
+	if ceil <= 0 || ceil > maxRetryDelay {
+		ceil = maxRetryDelay
+	}
+	b.attempt++
+	return time.Duration(rand.Int63n(int64(ceil))), true
+}
+
+// sleepCtx waits for d, returning early with ctx.Err() if ctx is canceled first.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
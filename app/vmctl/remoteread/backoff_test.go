@@ -0,0 +1,63 @@
+package remoteread
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBackoffNextBounds(t *testing.T) {
+	b := newBackoff()
+	for attempt := 0; attempt < maxRetryAttempts; attempt++ {
+		ceil := baseRetryDelay << uint(attempt)
+		if ceil <= 0 || ceil > maxRetryDelay {
+			ceil = maxRetryDelay
+		}
+		d, ok := b.next()
+		if !ok {
+			t.Fatalf("attempt %d: next() returned ok=false before maxRetryAttempts was reached", attempt)
+		}
+		if d < 0 || d > ceil {
+			t.Errorf("attempt %d: next() = %s; want within [0, %s]", attempt, d, ceil)
+		}
+	}
+	if d, ok := b.next(); ok {
+		t.Errorf("next() after %d attempts = (%s, true); want ok=false", maxRetryAttempts, d)
+	}
+}
+
+func TestBackoffNextCapsAtMaxRetryDelay(t *testing.T) {
+	b := newBackoff()
+	for attempt := 0; attempt < maxRetryAttempts; attempt++ {
+		d, ok := b.next()
+		if !ok {
+			t.Fatalf("attempt %d: unexpected ok=false", attempt)
+		}
+		if d > maxRetryDelay {
+			t.Errorf("attempt %d: next() = %s; want <= maxRetryDelay %s", attempt, d, maxRetryDelay)
+		}
+	}
+}
+
+func TestSleepCtxReturnsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	err := sleepCtx(ctx, time.Minute)
+	if err == nil {
+		t.Fatal("expected an error from sleepCtx on a canceled context, got nil")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("sleepCtx took %s to return after context cancellation; want near-immediate", elapsed)
+	}
+}
+
+func TestSleepCtxWaitsOutDuration(t *testing.T) {
+	start := time.Now()
+	if err := sleepCtx(context.Background(), 10*time.Millisecond); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Errorf("sleepCtx returned after %s; want at least 10ms", elapsed)
+	}
+}
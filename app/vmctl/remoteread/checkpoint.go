@@ -0,0 +1,145 @@
+package remoteread
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/prompb"
+)
+
+// defaultCheckpointDir is where file-backed checkpoints are stored when
+// Config.CheckpointDir isn't set, mirroring vmctl's other on-disk state.
+const defaultCheckpointDir = ".vmctl"
+
+// Checkpointer records, per series fingerprint, the last timestamp that was
+// successfully handed to a StreamCallback for a given Filter, so that
+// Client.Read can resume an interrupted import instead of starting over.
+type Checkpointer interface {
+	// Load returns the fingerprint -> last-timestamp-ms progress recorded
+	// for key. A missing checkpoint is not an error: it returns an empty map.
+	Load(key string) (map[uint64]int64, error)
+	// Save persists progress for key.
+	Save(key string, progress map[uint64]int64) error
+}
+
+// fileCheckpointer is the default Checkpointer: one JSON file per
+// checkpoint key under dir, fsynced on every Save.
+type fileCheckpointer struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileCheckpointer returns a Checkpointer that stores progress under dir
+// as "remoteread-<hash>.ckpt" files. If dir is empty, $HOME/.vmctl is used.
+func NewFileCheckpointer(dir string) (*fileCheckpointer, error) {
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("cannot determine home directory for checkpoint storage: %w", err)
+		}
+		dir = filepath.Join(home, defaultCheckpointDir)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("cannot create checkpoint dir %q: %w", dir, err)
+	}
+	return &fileCheckpointer{dir: dir}, nil
+}
+
+type checkpointEntry struct {
+	Fingerprint     uint64 `json:"fingerprint"`
+	LastTimestampMs int64  `json:"last_timestamp_ms"`
+}
+
+func (f *fileCheckpointer) path(key string) string {
+	return filepath.Join(f.dir, fmt.Sprintf("remoteread-%s.ckpt", key))
+}
+
+func (f *fileCheckpointer) Load(key string) (map[uint64]int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := os.ReadFile(f.path(key))
+	if os.IsNotExist(err) {
+		return make(map[uint64]int64), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read checkpoint %q: %w", f.path(key), err)
+	}
+	var entries []checkpointEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("cannot parse checkpoint %q: %w", f.path(key), err)
+	}
+	progress := make(map[uint64]int64, len(entries))
+	for _, e := range entries {
+		progress[e.Fingerprint] = e.LastTimestampMs
+	}
+	return progress, nil
+}
+
+// Save persists progress atomically: it writes to a temporary file, fsyncs
+// it, then renames it over the checkpoint file so a crash mid-write can
+// never leave a half-written checkpoint behind.
+func (f *fileCheckpointer) Save(key string, progress map[uint64]int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entries := make([]checkpointEntry, 0, len(progress))
+	for fp, ts := range progress {
+		entries = append(entries, checkpointEntry{Fingerprint: fp, LastTimestampMs: ts})
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("cannot marshal checkpoint: %w", err)
+	}
+
+	path := f.path(key)
+	tmp, err := os.CreateTemp(f.dir, "remoteread-*.ckpt.tmp")
+	if err != nil {
+		return fmt.Errorf("cannot create temporary checkpoint file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("cannot write checkpoint %q: %w", path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("cannot fsync checkpoint %q: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("cannot close checkpoint %q: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("cannot rename checkpoint into place %q: %w", path, err)
+	}
+	return nil
+}
+
+// checkpointKey derives a stable, filesystem-safe identifier for a
+// (tenant, filter) pair so distinct filters don't collide in the same
+// checkpoint file.
+func checkpointKey(filter *Filter, tenant string) string {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s\x00%d\x00%d\x00%s\x00%s\x00", tenant, filter.Min, filter.Max, filter.Selector, filter.Label+"="+filter.LabelValue)
+	for _, m := range filter.Matchers {
+		_, _ = fmt.Fprintf(h, "%s%s%s\x00", m.Label, m.Type, m.Value)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// seriesFingerprint returns a stable hash of a series' labels, used as the
+// checkpoint key for per-series resume progress.
+func seriesFingerprint(pbLabels []prompb.Label) uint64 {
+	b := labels.NewBuilder(labels.EmptyLabels())
+	for _, l := range pbLabels {
+		b.Set(l.Name, l.Value)
+	}
+	return b.Labels().Hash()
+}
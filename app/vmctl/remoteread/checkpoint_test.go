@@ -0,0 +1,53 @@
+package remoteread
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckpointKeyStable(t *testing.T) {
+	filter := &Filter{Min: 0, Max: 1000, Selector: `{job="api"}`}
+	k1 := checkpointKey(filter, "team-a")
+	k2 := checkpointKey(filter, "team-a")
+	if k1 != k2 {
+		t.Errorf("checkpointKey should be stable across calls with the same input, got %q and %q", k1, k2)
+	}
+
+	other := &Filter{Min: 0, Max: 1000, Selector: `{job="other"}`}
+	if checkpointKey(other, "team-a") == k1 {
+		t.Error("checkpointKey should differ for different filters")
+	}
+	if checkpointKey(filter, "team-b") == k1 {
+		t.Error("checkpointKey should differ for different tenants")
+	}
+}
+
+func TestFileCheckpointerSaveLoadRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	cp, err := NewFileCheckpointer(dir)
+	if err != nil {
+		t.Fatalf("cannot create checkpointer: %s", err)
+	}
+
+	key := "some-key"
+	loaded, err := cp.Load(key)
+	if err != nil {
+		t.Fatalf("unexpected error loading missing checkpoint: %s", err)
+	}
+	if len(loaded) != 0 {
+		t.Fatalf("expected empty progress for a missing checkpoint, got %v", loaded)
+	}
+
+	progress := map[uint64]int64{1: 100, 2: 200}
+	if err := cp.Save(key, progress); err != nil {
+		t.Fatalf("cannot save checkpoint: %s", err)
+	}
+
+	loaded, err = cp.Load(key)
+	if err != nil {
+		t.Fatalf("cannot load checkpoint: %s", err)
+	}
+	if !reflect.DeepEqual(loaded, progress) {
+		t.Errorf("Load() = %v; want %v", loaded, progress)
+	}
+}
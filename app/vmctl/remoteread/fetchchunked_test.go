@@ -0,0 +1,154 @@
+package remoteread
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/prometheus/prometheus/storage/remote"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+)
+
+// nopFlusher satisfies http.Flusher for remote.NewChunkedWriter in tests
+// that don't go through an actual HTTP response writer.
+type nopFlusher struct{}
+
+func (nopFlusher) Flush() {}
+
+func xorChunkBytes(t *testing.T, samples ...prompb.Sample) []byte {
+	t.Helper()
+	c := chunkenc.NewXORChunk()
+	app, err := c.Appender()
+	if err != nil {
+		t.Fatalf("cannot create XOR chunk appender: %s", err)
+	}
+	for _, s := range samples {
+		app.Append(s.Timestamp, s.Value)
+	}
+	return c.Bytes()
+}
+
+func histogramChunkBytes(t *testing.T, ts int64, h *histogram.Histogram) []byte {
+	t.Helper()
+	c := chunkenc.NewHistogramChunk()
+	app, err := c.Appender()
+	if err != nil {
+		t.Fatalf("cannot create histogram chunk appender: %s", err)
+	}
+	if _, _, _, err := app.AppendHistogram(nil, ts, h, true); err != nil {
+		t.Fatalf("cannot append histogram: %s", err)
+	}
+	return c.Bytes()
+}
+
+// writeChunkedFrame encodes res as a single chunked-read frame, the same
+// wire format remote.NewChunkedReader expects in fetchChunked.
+func writeChunkedFrame(t *testing.T, buf *bytes.Buffer, res *prompb.ChunkedReadResponse) {
+	t.Helper()
+	data, err := proto.Marshal(res)
+	if err != nil {
+		t.Fatalf("cannot marshal ChunkedReadResponse: %s", err)
+	}
+	w := remote.NewChunkedWriter(buf, nopFlusher{})
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("cannot write chunked frame: %s", err)
+	}
+}
+
+// TestFetchChunkedMultipleSeriesPerFrame asserts that a single frame
+// carrying more than one ChunkedSeries - legal per the protobuf schema and
+// something a non-Prometheus source is more likely to send - decodes each
+// series independently instead of leaking labels/samples from one series
+// into the next.
+func TestFetchChunkedMultipleSeriesPerFrame(t *testing.T) {
+	var buf bytes.Buffer
+	writeChunkedFrame(t, &buf, &prompb.ChunkedReadResponse{
+		ChunkedSeries: []*prompb.ChunkedSeries{
+			{
+				Labels: []prompb.Label{{Name: "__name__", Value: "a"}},
+				Chunks: []prompb.Chunk{{
+					Type: prompb.Chunk_XOR,
+					Data: xorChunkBytes(t, prompb.Sample{Timestamp: 1, Value: 1}),
+				}},
+			},
+			{
+				Labels: []prompb.Label{{Name: "__name__", Value: "b"}, {Name: "job", Value: "x"}},
+				Chunks: []prompb.Chunk{{
+					Type: prompb.Chunk_XOR,
+					Data: xorChunkBytes(t, prompb.Sample{Timestamp: 2, Value: 2}),
+				}},
+			},
+		},
+	})
+
+	c := &Client{}
+	var got []prompb.TimeSeries
+	err := c.fetchChunked(&buf, func(ts prompb.TimeSeries) error {
+		got = append(got, ts)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("fetchChunked() error: %s", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 series, got %d: %v", len(got), got)
+	}
+	if !reflect.DeepEqual(got[0].Labels, []prompb.Label{{Name: "__name__", Value: "a"}}) {
+		t.Errorf("series 0 labels = %v; want only its own labels", got[0].Labels)
+	}
+	if !reflect.DeepEqual(got[1].Labels, []prompb.Label{{Name: "__name__", Value: "b"}, {Name: "job", Value: "x"}}) {
+		t.Errorf("series 1 labels = %v; want only its own labels, not leaked from series 0", got[1].Labels)
+	}
+	if len(got[0].Samples) != 1 || len(got[1].Samples) != 1 {
+		t.Errorf("expected 1 sample per series, got %d and %d", len(got[0].Samples), len(got[1].Samples))
+	}
+}
+
+// TestFetchChunkedHistogram exercises end-to-end decoding of a
+// HISTOGRAM-encoded chunk through fetchChunked.
+func TestFetchChunkedHistogram(t *testing.T) {
+	h := &histogram.Histogram{
+		Schema:          0,
+		Count:           10,
+		Sum:             12.5,
+		ZeroThreshold:   0.001,
+		PositiveSpans:   []histogram.Span{{Offset: 0, Length: 1}},
+		PositiveBuckets: []int64{10},
+	}
+
+	var buf bytes.Buffer
+	writeChunkedFrame(t, &buf, &prompb.ChunkedReadResponse{
+		ChunkedSeries: []*prompb.ChunkedSeries{{
+			Labels: []prompb.Label{{Name: "__name__", Value: "req_duration"}},
+			Chunks: []prompb.Chunk{{
+				Type: prompb.Chunk_HISTOGRAM,
+				Data: histogramChunkBytes(t, 5, h),
+			}},
+		}},
+	})
+
+	c := &Client{}
+	var got []prompb.TimeSeries
+	err := c.fetchChunked(&buf, func(ts prompb.TimeSeries) error {
+		got = append(got, ts)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("fetchChunked() error: %s", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected 1 series, got %d", len(got))
+	}
+	if len(got[0].Samples) != 0 {
+		t.Errorf("expected no float samples for a histogram chunk, got %d", len(got[0].Samples))
+	}
+	if len(got[0].Histograms) != 1 {
+		t.Fatalf("expected 1 histogram sample, got %d", len(got[0].Histograms))
+	}
+	if ts := got[0].Histograms[0].Timestamp; ts != 5 {
+		t.Errorf("histogram timestamp = %d; want 5", ts)
+	}
+}
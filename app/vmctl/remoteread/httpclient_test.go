@@ -0,0 +1,118 @@
+package remoteread
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestNewRoundTripperAuthPrecedence asserts that when more than one of
+// basic auth, bearer token and OAuth2 are configured at once, the strongest
+// scheme wins instead of basic auth clobbering an already-set Authorization
+// header.
+func TestNewRoundTripperAuthPrecedence(t *testing.T) {
+	oauthServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"oauth2-token","token_type":"Bearer"}`))
+	}))
+	defer oauthServer.Close()
+	oauth2Cfg := &OAuth2Config{
+		ClientID:     "client",
+		ClientSecret: "secret",
+		TokenURL:     oauthServer.URL,
+	}
+
+	f := func(name string, cfg Config, want string) {
+		t.Helper()
+		t.Run(name, func(t *testing.T) {
+			var gotAuth string
+			target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotAuth = r.Header.Get("Authorization")
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer target.Close()
+
+			rt, err := newRoundTripper(cfg)
+			if err != nil {
+				t.Fatalf("newRoundTripper() error: %s", err)
+			}
+			client := &http.Client{Transport: rt}
+			req, err := http.NewRequest(http.MethodGet, target.URL, nil)
+			if err != nil {
+				t.Fatalf("cannot build request: %s", err)
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("client.Do() error: %s", err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			if gotAuth != want {
+				t.Errorf("Authorization header = %q; want %q", gotAuth, want)
+			}
+		})
+	}
+
+	f("basic auth alone", Config{
+		Username: "user",
+		Password: "pass",
+	}, "Basic dXNlcjpwYXNz")
+
+	f("bearer token wins over basic auth", Config{
+		Username:    "user",
+		Password:    "pass",
+		BearerToken: "tok",
+	}, "Bearer tok")
+
+	f("oauth2 wins over bearer token and basic auth", Config{
+		Username:    "user",
+		Password:    "pass",
+		BearerToken: "tok",
+		OAuth2:      oauth2Cfg,
+	}, "Bearer oauth2-token")
+
+	f("oauth2 wins over basic auth with no bearer token", Config{
+		Username: "user",
+		Password: "pass",
+		OAuth2:   oauth2Cfg,
+	}, "Bearer oauth2-token")
+}
+
+// TestNewRoundTripperCustomHeadersSurviveAuth asserts custom headers are
+// still applied to the outgoing request alongside whichever auth scheme
+// wins, i.e. the headersRoundTripper stays innermost in the chain.
+func TestNewRoundTripperCustomHeadersSurviveAuth(t *testing.T) {
+	var gotCustom, gotAuth string
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotCustom = r.Header.Get("X-Custom")
+		gotAuth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	cfg := Config{
+		Headers:     map[string]string{"X-Custom": "value"},
+		BearerToken: "tok",
+	}
+	rt, err := newRoundTripper(cfg)
+	if err != nil {
+		t.Fatalf("newRoundTripper() error: %s", err)
+	}
+	client := &http.Client{Transport: rt}
+	req, err := http.NewRequest(http.MethodGet, target.URL, nil)
+	if err != nil {
+		t.Fatalf("cannot build request: %s", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("client.Do() error: %s", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if gotCustom != "value" {
+		t.Errorf("X-Custom header = %q; want %q", gotCustom, "value")
+	}
+	if gotAuth != "Bearer tok" {
+		t.Errorf("Authorization header = %q; want %q", gotAuth, "Bearer tok")
+	}
+}
@@ -3,11 +3,11 @@ package remoteread
 import (
 	"bytes"
 	"context"
-	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bytesutil"
@@ -16,8 +16,10 @@ import (
 	"github.com/golang/snappy"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/prompb"
+	"github.com/prometheus/prometheus/promql/parser"
 	"github.com/prometheus/prometheus/storage/remote"
 	"github.com/prometheus/prometheus/tsdb/chunkenc"
+	"golang.org/x/sync/semaphore"
 )
 
 var bodyBufferPool bytesutil.ByteBufferPool
@@ -26,6 +28,30 @@ const (
 	defaultReadTimeout = 30 * time.Second
 	remoteReadPath     = "/api/v1/read"
 	healthPath         = "/health"
+
+	// defaultTenantHeader is the header Cortex/Mimir/Thanos-receive use to
+	// scope a request to a single tenant.
+	defaultTenantHeader = "X-Scope-OrgID"
+	// defaultTenantLabel tags imported series with the tenant they came
+	// from whenever a non-empty tenant is configured.
+	defaultTenantLabel = "vm_account_id"
+
+	// checkpointFlushInterval is how many series are streamed between
+	// fsyncs of the checkpoint, trading a bit of duplicated work on crash
+	// for not hitting disk on every single series.
+	checkpointFlushInterval = 200
+	// minRetryWindowMs bounds how small readWindowed will shrink a failing
+	// window, in milliseconds, so retries don't degenerate to one sample at a time.
+	minRetryWindowMs = int64(5 * time.Minute / time.Millisecond)
+
+	// defaultMaxBytesInFlight bounds how many bytes worth of concurrent
+	// range reads are allowed in flight at once, see Config.MaxBytesInFlight.
+	defaultMaxBytesInFlight = 256 << 20
+	// assumedBytesPerRangeRead is the weight a single in-flight range read
+	// takes from the bytes gate. The real response size isn't known until
+	// after the read completes, so this is a conservative per-query estimate
+	// rather than a measurement.
+	assumedBytesPerRangeRead = 16 << 20
 )
 
 // StreamCallback is a callback function for processing time series
@@ -34,10 +60,16 @@ type StreamCallback func(series prompb.TimeSeries) error
 // Client is an HTTP client for reading
 // time series via remote read protocol.
 type Client struct {
-	addr     string
-	c        *http.Client
-	user     string
-	password string
+	addr                  string
+	c                     *http.Client
+	acceptedResponseTypes []prompb.ReadRequest_ResponseType
+	tenants               []string
+	tenantHeader          string
+	tenantLabel           string
+	checkpointer          Checkpointer
+	concurrency           int
+	stepMs                int64
+	bytesGate             *semaphore.Weighted
 }
 
 // Config is config for remote read.
@@ -51,15 +83,114 @@ type Config struct {
 	Username string
 	// Password is the remote read password, optional.
 	Password string
+	// AcceptedResponseTypes is the list of response types the source may
+	// reply with, in order of preference. Defaults to
+	// [STREAMED_XOR_CHUNKS, SAMPLES] so sources that don't implement
+	// chunked streaming (older Prometheus, Cortex, some gateways) still work.
+	AcceptedResponseTypes []prompb.ReadRequest_ResponseType
+
+	// TLSConfig is used for HTTPS connections to the remote read source, optional.
+	TLSConfig *TLSConfig
+	// BearerToken is the bearer token used for authentication, optional.
+	BearerToken string
+	// BearerTokenFile is a path to a file containing the bearer token used
+	// for authentication, optional. Takes precedence over BearerToken.
+	BearerTokenFile string
+	// OAuth2 is the OAuth2 client-credentials config used for authentication, optional.
+	OAuth2 *OAuth2Config
+	// ProxyURL is the URL of an HTTP proxy to use for requests, optional.
+	ProxyURL string
+	// Headers is a set of custom HTTP headers added to every request, optional.
+	Headers map[string]string
+
+	// Tenant is a single tenant to read from, optional. It is combined with
+	// Tenants and TenantsFile into the full list of tenants to read.
+	Tenant string
+	// Tenants is a list of tenants to issue one read per tenant for, optional.
+	Tenants []string
+	// TenantsFile is a path to a file with newline-separated tenant IDs, optional.
+	TenantsFile string
+	// TenantHeader is the HTTP header used to pass the tenant ID to the
+	// remote read source, e.g. Cortex/Mimir/Thanos' X-Scope-OrgID.
+	// Defaults to defaultTenantHeader.
+	TenantHeader string
+	// TenantLabel is the label name used to tag every series pulled for a
+	// given non-empty tenant, so multi-tenant sources can be migrated in a
+	// single run. Defaults to defaultTenantLabel.
+	TenantLabel string
+
+	// Checkpointer overrides the default file-backed checkpointer used to
+	// make imports resumable, optional. Takes precedence over CheckpointDir.
+	Checkpointer Checkpointer
+	// CheckpointDir is where the default file-backed Checkpointer stores its
+	// progress files, optional. Defaults to "$HOME/.vmctl".
+	CheckpointDir string
+	// DisableCheckpoint turns off resumable imports entirely, reverting to
+	// the old behavior of always reading filter.Min..filter.Max from scratch.
+	DisableCheckpoint bool
+
+	// Concurrency is how many StepDuration-sized sub-ranges of a Filter are
+	// read in parallel, optional. Values <= 1 preserve the single-threaded
+	// behavior of reading the whole filter as one query.
+	Concurrency int
+	// StepDuration is the size of the sub-ranges a Filter is split into when
+	// Concurrency > 1, optional. It has no effect when Concurrency <= 1.
+	StepDuration time.Duration
+	// MaxBytesInFlight bounds how many bytes worth of concurrent range
+	// reads are allowed in flight at once, optional. Defaults to
+	// defaultMaxBytesInFlight. Only takes effect when Concurrency > 1.
+	MaxBytesInFlight int64
 
 	transport *http.Transport
 }
 
+func defaultAcceptedResponseTypes() []prompb.ReadRequest_ResponseType {
+	return []prompb.ReadRequest_ResponseType{
+		prompb.ReadRequest_STREAMED_XOR_CHUNKS,
+		prompb.ReadRequest_SAMPLES,
+	}
+}
+
+// MatcherType is the type of a label matcher used in Filter.Matchers.
+type MatcherType string
+
+// Supported matcher types, mirroring labels.MatchType.
+const (
+	MatchEqual     MatcherType = "EQ"
+	MatchNotEqual  MatcherType = "NEQ"
+	MatchRegexp    MatcherType = "RE"
+	MatchNotRegexp MatcherType = "NRE"
+)
+
+// Matcher is a single label matcher that can be combined with others
+// to select a subset of series for remote read, analogous to a single
+// term of a PromQL selector, e.g. `job="api"` or `instance!~"canary-.*"`.
+type Matcher struct {
+	Label string
+	Value string
+	Type  MatcherType
+}
+
 // Filter is used for request remote read data by filter
 type Filter struct {
-	Min, Max   int64
+	Min, Max int64
+
+	// Label and LabelValue are kept for backward compatibility and are
+	// converted to a single MatchRegexp matcher when Matchers and
+	// Selector are both empty.
+	//
+	// Deprecated: use Matchers or Selector instead.
 	Label      string
 	LabelValue string
+
+	// Matchers is a list of label matchers combined with logical AND.
+	// It takes precedence over Label/LabelValue when non-empty.
+	Matchers []Matcher
+
+	// Selector is a raw PromQL metric selector, e.g.
+	// `{job="api", instance!~"canary-.*", __name__=~"http_.*"}`.
+	// It takes precedence over Matchers and Label/LabelValue when set.
+	Selector string
 }
 
 // NewClient returns client for
@@ -71,21 +202,242 @@ func NewClient(cfg Config) (*Client, error) {
 	if cfg.ReadTimeout == 0 {
 		cfg.ReadTimeout = defaultReadTimeout
 	}
+	acceptedResponseTypes := cfg.AcceptedResponseTypes
+	if len(acceptedResponseTypes) == 0 {
+		acceptedResponseTypes = defaultAcceptedResponseTypes()
+	}
+	rt, err := newRoundTripper(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build HTTP transport: %w", err)
+	}
+	tenants, err := resolveTenants(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve tenants: %w", err)
+	}
+	tenantHeader := cfg.TenantHeader
+	if tenantHeader == "" {
+		tenantHeader = defaultTenantHeader
+	}
+	tenantLabel := cfg.TenantLabel
+	if tenantLabel == "" {
+		tenantLabel = defaultTenantLabel
+	}
+	checkpointer := cfg.Checkpointer
+	if cfg.DisableCheckpoint {
+		checkpointer = nil
+	} else if checkpointer == nil {
+		checkpointer, err = NewFileCheckpointer(cfg.CheckpointDir)
+		if err != nil {
+			return nil, fmt.Errorf("cannot initialize checkpoint storage: %w", err)
+		}
+	}
+	concurrency := cfg.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	var bytesGate *semaphore.Weighted
+	if concurrency > 1 {
+		maxBytesInFlight := cfg.MaxBytesInFlight
+		if maxBytesInFlight <= 0 {
+			maxBytesInFlight = defaultMaxBytesInFlight
+		}
+		// Acquire always asks for assumedBytesPerRangeRead at once, so the
+		// gate's total size must be at least that much or Acquire would
+		// block forever. A MaxBytesInFlight set below that floor degrades
+		// to serialized range reads instead of deadlocking.
+		if maxBytesInFlight < assumedBytesPerRangeRead {
+			maxBytesInFlight = assumedBytesPerRangeRead
+		}
+		bytesGate = semaphore.NewWeighted(maxBytesInFlight)
+	}
 
 	c := &Client{
 		c: &http.Client{
 			Timeout:   cfg.ReadTimeout,
-			Transport: http.DefaultTransport.(*http.Transport).Clone(),
+			Transport: rt,
 		},
-		addr:     strings.TrimSuffix(cfg.Addr, "/"),
-		user:     cfg.Username,
-		password: cfg.Password,
+		addr:                  strings.TrimSuffix(cfg.Addr, "/"),
+		acceptedResponseTypes: acceptedResponseTypes,
+		tenants:               tenants,
+		tenantHeader:          tenantHeader,
+		tenantLabel:           tenantLabel,
+		checkpointer:          checkpointer,
+		concurrency:           concurrency,
+		stepMs:                int64(cfg.StepDuration / time.Millisecond),
+		bytesGate:             bytesGate,
 	}
 	return c, nil
 }
 
-// Read fetch data from remote read source
+// Read fetch data from remote read source. It issues one read per
+// configured tenant, tagging series pulled for a non-default tenant with
+// c.tenantLabel so a multi-tenant source can be migrated in a single run.
 func (c *Client) Read(ctx context.Context, filter *Filter, streamCb StreamCallback) error {
+	for _, tenant := range c.tenants {
+		cb := streamCb
+		if tenant != "" {
+			cb = func(ts prompb.TimeSeries) error {
+				ts.Labels = append(ts.Labels, prompb.Label{Name: c.tenantLabel, Value: tenant})
+				return streamCb(ts)
+			}
+		}
+		if err := c.readTenant(ctx, tenant, filter, cb); err != nil {
+			return fmt.Errorf("error reading tenant %q: %w", tenant, err)
+		}
+	}
+	return nil
+}
+
+// readTenant reads filter for a single tenant. When c.concurrency > 1 and
+// c.stepMs is set, it splits [filter.Min, filter.Max) into step-sized
+// sub-ranges and reads them concurrently through a bounded worker pool,
+// gated on an estimated amount of in-flight bytes so aggressive concurrency
+// can't overwhelm the source; otherwise it reads the filter as one range.
+func (c *Client) readTenant(ctx context.Context, tenant string, filter *Filter, streamCb StreamCallback) error {
+	ranges := splitRange(filter.Min, filter.Max, c.stepMs)
+	if c.concurrency <= 1 || len(ranges) <= 1 {
+		return c.readRangeChunk(ctx, tenant, filter, streamCb)
+	}
+
+	var mu sync.Mutex
+	guardedCb := func(ts prompb.TimeSeries) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return streamCb(ts)
+	}
+
+	sem := make(chan struct{}, c.concurrency)
+	var wg sync.WaitGroup
+	errs := make([]error, len(ranges))
+	for i, r := range ranges {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return ctx.Err()
+		}
+		wg.Add(1)
+		go func(i int, r timeRange) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if c.bytesGate != nil {
+				if err := c.bytesGate.Acquire(ctx, assumedBytesPerRangeRead); err != nil {
+					errs[i] = err
+					return
+				}
+				defer c.bytesGate.Release(assumedBytesPerRangeRead)
+			}
+			sub := *filter
+			sub.Min, sub.Max = r.lo, r.hi
+			errs[i] = c.readRangeChunk(ctx, tenant, &sub, guardedCb)
+		}(i, r)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readRangeChunk reads a single [filter.Min, filter.Max) range for tenant,
+// resuming from and periodically updating an on-disk checkpoint so an
+// import interrupted mid-stream can pick up where it left off instead of
+// starting over.
+func (c *Client) readRangeChunk(ctx context.Context, tenant string, filter *Filter, streamCb StreamCallback) error {
+	key := checkpointKey(filter, tenant)
+	progress := make(map[uint64]int64)
+	if c.checkpointer != nil {
+		loaded, err := c.checkpointer.Load(key)
+		if err != nil {
+			return fmt.Errorf("cannot load checkpoint: %w", err)
+		}
+		progress = loaded
+	}
+	cb := c.checkpointingCallback(key, progress, streamCb)
+	err := c.readWindowed(ctx, tenant, filter, cb)
+	if saveErr := c.saveProgress(key, progress); saveErr != nil {
+		logger.Errorf("cannot persist checkpoint for tenant %q: %s", tenant, saveErr)
+	}
+	return err
+}
+
+// readWindowed reads [filter.Min, filter.Max) as a single query, the common
+// case. A retryable error (network failure, 5xx) re-reads the same window
+// from its start, shrinking it on every further failure so a persistently
+// misbehaving source degrades to small, retriable chunks instead of
+// restarting the whole import from filter.Min. The window is re-read from
+// its own start rather than jumping ahead to the checkpointed watermark:
+// when a checkpointer is configured, cb is already idempotent for series
+// that made it through (see checkpointingCallback), but a series that hadn't been streamed yet when
+// the failure hit has no entry in the checkpoint at all, so jumping ahead
+// would skip its samples in [lo, watermark) and silently lose data.
+func (c *Client) readWindowed(ctx context.Context, tenant string, filter *Filter, cb StreamCallback) error {
+	lo, hi := filter.Min, filter.Max
+	window := hi - lo
+	bo := newBackoff()
+	for lo < hi {
+		sub := *filter
+		sub.Min, sub.Max = lo, lo+window
+		if sub.Max > hi {
+			sub.Max = hi
+		}
+		err := c.readRange(ctx, tenant, &sub, cb)
+		if err == nil {
+			lo = sub.Max
+			bo = newBackoff()
+			window = hi - lo
+			continue
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !isRetryable(err) {
+			return err
+		}
+		wait, ok := bo.next()
+		if !ok {
+			return fmt.Errorf("giving up reading [%d,%d) for tenant %q after repeated errors: %w", lo, hi, tenant, err)
+		}
+		logger.Errorf("attempt to fetch data from remote storage failed, retrying in %s: %s", wait, err)
+		if err := sleepCtx(ctx, wait); err != nil {
+			return err
+		}
+		if window > minRetryWindowMs {
+			window /= 2
+			if window < minRetryWindowMs {
+				window = minRetryWindowMs
+			}
+		}
+	}
+	return nil
+}
+
+// timeRange is a half-open [lo, hi) millisecond timestamp range.
+type timeRange struct {
+	lo, hi int64
+}
+
+// splitRange splits [min, max) into stepMs-sized sub-ranges. A stepMs <= 0,
+// or a range no larger than a single step, yields the whole range unsplit.
+func splitRange(min, max, stepMs int64) []timeRange {
+	if stepMs <= 0 || max-min <= stepMs {
+		return []timeRange{{lo: min, hi: max}}
+	}
+	ranges := make([]timeRange, 0, (max-min)/stepMs+1)
+	for lo := min; lo < max; lo += stepMs {
+		hi := lo + stepMs
+		if hi > max {
+			hi = max
+		}
+		ranges = append(ranges, timeRange{lo: lo, hi: hi})
+	}
+	return ranges
+}
+
+func (c *Client) readRange(ctx context.Context, tenant string, filter *Filter, streamCb StreamCallback) error {
 	query, err := c.query(filter)
 	if err != nil {
 		return fmt.Errorf("error prepare stream query: %w", err)
@@ -94,29 +446,88 @@ func (c *Client) Read(ctx context.Context, filter *Filter, streamCb StreamCallba
 		Queries: []*prompb.Query{
 			query,
 		},
-		AcceptedResponseTypes: []prompb.ReadRequest_ResponseType{prompb.ReadRequest_STREAMED_XOR_CHUNKS},
+		AcceptedResponseTypes: c.acceptedResponseTypes,
 	}
 	data, err := proto.Marshal(req)
 	if err != nil {
 		return fmt.Errorf("unable to marshal read request: %w", err)
 	}
+	return c.fetch(ctx, tenant, snappy.Encode(nil, data), streamCb)
+}
 
-	const attempts = 5
-	b := snappy.Encode(nil, data)
-	for i := 0; i < attempts; i++ {
-		err := c.fetch(ctx, b, streamCb)
-		if err != nil {
-			if errors.Is(err, context.Canceled) {
-				return fmt.Errorf("process stoped")
+// checkpointingCallback wraps streamCb so that, for each series, only
+// samples/histograms past the checkpointed last-timestamp are forwarded,
+// and the in-memory progress map is advanced and periodically fsynced.
+func (c *Client) checkpointingCallback(key string, progress map[uint64]int64, streamCb StreamCallback) StreamCallback {
+	if c.checkpointer == nil {
+		return streamCb
+	}
+	var delivered int
+	return func(ts prompb.TimeSeries) error {
+		fp := seriesFingerprint(ts.Labels)
+		last, seen := progress[fp]
+		if seen {
+			ts.Samples = filterAfter(ts.Samples, last)
+			ts.Histograms = filterHistogramsAfter(ts.Histograms, last)
+			if len(ts.Samples) == 0 && len(ts.Histograms) == 0 {
+				return nil
+			}
+		}
+		if err := streamCb(ts); err != nil {
+			return err
+		}
+		progress[fp] = maxTimestamp(ts, last)
+
+		delivered++
+		if delivered%checkpointFlushInterval == 0 {
+			if err := c.checkpointer.Save(key, progress); err != nil {
+				return fmt.Errorf("cannot persist checkpoint: %w", err)
 			}
-			logger.Errorf("attempt %d to fetch data from remote storage: %s", i+1, err)
-			// sleeping to avoid remote db hammering
-			time.Sleep(time.Second)
-			continue
 		}
 		return nil
 	}
-	return nil
+}
+
+func (c *Client) saveProgress(key string, progress map[uint64]int64) error {
+	if c.checkpointer == nil {
+		return nil
+	}
+	return c.checkpointer.Save(key, progress)
+}
+
+func filterAfter(samples []prompb.Sample, after int64) []prompb.Sample {
+	out := samples[:0]
+	for _, s := range samples {
+		if s.Timestamp > after {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func filterHistogramsAfter(histograms []prompb.Histogram, after int64) []prompb.Histogram {
+	out := histograms[:0]
+	for _, h := range histograms {
+		if h.Timestamp > after {
+			out = append(out, h)
+		}
+	}
+	return out
+}
+
+func maxTimestamp(ts prompb.TimeSeries, floor int64) int64 {
+	m := floor
+	for _, s := range ts.Samples {
+		if s.Timestamp > m {
+			m = s.Timestamp
+		}
+	}
+	for _, h := range ts.Histograms {
+		if h.Timestamp > m {
+			m = h.Timestamp
+		}
+	}
+	return m
 }
 
 // Ping checks the health of the read source
@@ -126,10 +537,10 @@ func (c *Client) Ping() error {
 	if err != nil {
 		return fmt.Errorf("cannot create request to %q: %s", url, err)
 	}
-	if c.user != "" {
-		req.SetBasicAuth(c.user, c.password)
+	if len(c.tenants) > 0 {
+		c.setTenantHeader(req, c.tenants[0])
 	}
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.c.Do(req)
 	if err != nil {
 		return err
 	}
@@ -139,7 +550,14 @@ func (c *Client) Ping() error {
 	return nil
 }
 
-func (c *Client) fetch(ctx context.Context, data []byte, streamCb StreamCallback) error {
+func (c *Client) setTenantHeader(req *http.Request, tenant string) {
+	if c.tenantHeader == "" || tenant == "" {
+		return
+	}
+	req.Header.Set(c.tenantHeader, tenant)
+}
+
+func (c *Client) fetch(ctx context.Context, tenant string, data []byte, streamCb StreamCallback) error {
 	r := bytes.NewReader(data)
 	url := c.addr + remoteReadPath
 	req, err := http.NewRequest("POST", url, r)
@@ -151,31 +569,44 @@ func (c *Client) fetch(ctx context.Context, data []byte, streamCb StreamCallback
 	req.Header.Add("Accept-Encoding", "snappy")
 	req.Header.Set("Content-Type", "application/x-protobuf")
 	req.Header.Set("X-Prometheus-Remote-Read-Version", "0.1.0")
-
-	if c.user != "" {
-		req.SetBasicAuth(c.user, c.password)
-	}
+	c.setTenantHeader(req, tenant)
 
 	resp, err := c.c.Do(req.WithContext(ctx))
 	if err != nil {
-		return fmt.Errorf("error while sending request to %s: %w; Data len %d(%d)",
-			req.URL.Redacted(), err, len(data), r.Size())
+		return &retryableError{err: fmt.Errorf("error while sending request to %s: %w; Data len %d(%d)",
+			req.URL.Redacted(), err, len(data), r.Size())}
 	}
 	defer func() { _ = resp.Body.Close() }()
 	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("unexpected response code %d for %s. Response body %q",
+		err := fmt.Errorf("unexpected response code %d for %s. Response body %q",
 			resp.StatusCode, req.URL.Redacted(), body)
+		if resp.StatusCode >= 500 {
+			return &retryableError{err: err}
+		}
+		return err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if isChunkedResponse(resp) {
+		return c.fetchChunked(resp.Body, streamCb)
+	}
+	return c.fetchSamples(resp.Body, streamCb)
+}
+
+// isChunkedResponse reports whether resp carries a STREAMED_XOR_CHUNKS
+// response, as opposed to a plain, non-chunked prompb.ReadResponse.
+func isChunkedResponse(resp *http.Response) bool {
+	return resp.Header.Get("Content-Type") == "application/x-streamed-protobuf; proto=prometheus.ChunkedReadResponse"
+}
+
+func (c *Client) fetchChunked(body io.Reader, streamCb StreamCallback) error {
 	bb := bodyBufferPool.Get()
 	defer bodyBufferPool.Put(bb)
 
-	stream := remote.NewChunkedReader(resp.Body, remote.DefaultChunkedReadLimit, bb.B)
+	stream := remote.NewChunkedReader(body, remote.DefaultChunkedReadLimit, bb.B)
 
 	for {
-		var ts prompb.TimeSeries
 		res := &prompb.ChunkedReadResponse{}
 		err := stream.NextProto(res)
 		if err == io.EOF {
@@ -185,13 +616,15 @@ func (c *Client) fetch(ctx context.Context, data []byte, streamCb StreamCallback
 			return err
 		}
 		for _, series := range res.ChunkedSeries {
+			var ts prompb.TimeSeries
 			ts.Labels = append(ts.Labels, series.Labels...)
 			for _, chunk := range series.Chunks {
-				samples, err := parseSamples(chunk.Data)
+				samples, histograms, err := parseSamples(chunk.Type, chunk.Data)
 				if err != nil {
 					return err
 				}
 				ts.Samples = append(ts.Samples, samples...)
+				ts.Histograms = append(ts.Histograms, histograms...)
 			}
 			if err := streamCb(ts); err != nil {
 				return err
@@ -201,24 +634,91 @@ func (c *Client) fetch(ctx context.Context, data []byte, streamCb StreamCallback
 	return nil
 }
 
-func (c *Client) query(filter *Filter) (*prompb.Query, error) {
-	var ms *labels.Matcher
-	if filter.Label == "" && filter.LabelValue == "" {
-		ms = labels.MustNewMatcher(labels.MatchRegexp, labels.MetricName, ".+")
-	} else {
-		ms = labels.MustNewMatcher(labels.MatchRegexp, filter.Label, filter.LabelValue)
+// fetchSamples handles the non-chunked SAMPLES response type: the whole
+// prompb.ReadResponse is read into memory and unmarshaled at once, since
+// Prometheus' remote read protocol doesn't support streaming it.
+func (c *Client) fetchSamples(body io.Reader, streamCb StreamCallback) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return fmt.Errorf("cannot read response body: %w", err)
+	}
+	var res prompb.ReadResponse
+	if err := proto.Unmarshal(data, &res); err != nil {
+		return fmt.Errorf("cannot unmarshal read response: %w", err)
 	}
-	m, err := toLabelMatchers(ms)
+	for _, qr := range res.Results {
+		for _, ts := range qr.Timeseries {
+			if err := streamCb(*ts); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Client) query(filter *Filter) (*prompb.Query, error) {
+	lms, err := filterMatchers(filter)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("error parse filter matchers: %w", err)
+	}
+	pbms := make([]*prompb.LabelMatcher, 0, len(lms))
+	for _, lm := range lms {
+		m, err := toLabelMatchers(lm)
+		if err != nil {
+			return nil, err
+		}
+		pbms = append(pbms, m)
 	}
 	return &prompb.Query{
 		StartTimestampMs: filter.Min,
 		EndTimestampMs:   filter.Max - 1,
-		Matchers:         []*prompb.LabelMatcher{m},
+		Matchers:         pbms,
 	}, nil
 }
 
+// filterMatchers resolves the effective list of labels.Matcher for the given
+// filter. Selector takes precedence over Matchers, which takes precedence
+// over the legacy Label/LabelValue pair.
+func filterMatchers(filter *Filter) ([]*labels.Matcher, error) {
+	if filter.Selector != "" {
+		return parser.ParseMetricSelector(filter.Selector)
+	}
+	if len(filter.Matchers) > 0 {
+		lms := make([]*labels.Matcher, 0, len(filter.Matchers))
+		for _, m := range filter.Matchers {
+			mt, err := toLabelsMatchType(m.Type)
+			if err != nil {
+				return nil, err
+			}
+			lm, err := labels.NewMatcher(mt, m.Label, m.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid matcher %s%s%q: %w", m.Label, m.Type, m.Value, err)
+			}
+			lms = append(lms, lm)
+		}
+		return lms, nil
+	}
+	if filter.Label == "" && filter.LabelValue == "" {
+		return []*labels.Matcher{labels.MustNewMatcher(labels.MatchRegexp, labels.MetricName, ".+")}, nil
+	}
+	return []*labels.Matcher{labels.MustNewMatcher(labels.MatchRegexp, filter.Label, filter.LabelValue)}, nil
+}
+
+func toLabelsMatchType(mt MatcherType) (labels.MatchType, error) {
+	switch mt {
+	case MatchEqual, "":
+		return labels.MatchEqual, nil
+	case MatchNotEqual:
+		return labels.MatchNotEqual, nil
+	case MatchRegexp:
+		return labels.MatchRegexp, nil
+	case MatchNotRegexp:
+		return labels.MatchNotRegexp, nil
+	default:
+		return 0, fmt.Errorf("unsupported matcher type %q", mt)
+	}
+}
+
 func toLabelMatchers(matcher *labels.Matcher) (*prompb.LabelMatcher, error) {
 	var mType prompb.LabelMatcher_Type
 	switch matcher.Type {
@@ -240,26 +740,62 @@ func toLabelMatchers(matcher *labels.Matcher) (*prompb.LabelMatcher, error) {
 	}, nil
 }
 
-func parseSamples(chunk []byte) ([]prompb.Sample, error) {
-	c, err := chunkenc.FromData(chunkenc.EncXOR, chunk)
+// parseSamples decodes a single chunk of the given prompb.Chunk_Encoding
+// into samples and/or native histogram samples, depending on the encoding.
+func parseSamples(typ prompb.Chunk_Encoding, chunk []byte) ([]prompb.Sample, []prompb.Histogram, error) {
+	enc, err := toChunkEncoding(typ)
+	if err != nil {
+		return nil, nil, err
+	}
+	c, err := chunkenc.FromData(enc, chunk)
 	if err != nil {
-		return nil, fmt.Errorf("error read chunk: %w", err)
+		return nil, nil, fmt.Errorf("error read chunk: %w", err)
 	}
 
-	var samples []prompb.Sample
 	it := c.Iterator(nil)
-	for it.Next() {
-		if it.Err() != nil {
-			return nil, fmt.Errorf("error iterate over chunks: %w", it.Err())
+	switch enc {
+	case chunkenc.EncHistogram:
+		var histograms []prompb.Histogram
+		for it.Next() == chunkenc.ValHistogram {
+			if it.Err() != nil {
+				return nil, nil, fmt.Errorf("error iterate over chunks: %w", it.Err())
+			}
+			ts, h := it.AtHistogram(nil)
+			histograms = append(histograms, remote.HistogramToHistogramProto(ts, h))
 		}
-
-		ts, v := it.At()
-		s := prompb.Sample{
-			Timestamp: ts,
-			Value:     v,
+		return nil, histograms, it.Err()
+	case chunkenc.EncFloatHistogram:
+		var histograms []prompb.Histogram
+		for it.Next() == chunkenc.ValFloatHistogram {
+			if it.Err() != nil {
+				return nil, nil, fmt.Errorf("error iterate over chunks: %w", it.Err())
+			}
+			ts, h := it.AtFloatHistogram(nil)
+			histograms = append(histograms, remote.FloatHistogramToHistogramProto(ts, h))
+		}
+		return nil, histograms, it.Err()
+	default:
+		var samples []prompb.Sample
+		for it.Next() == chunkenc.ValFloat {
+			if it.Err() != nil {
+				return nil, nil, fmt.Errorf("error iterate over chunks: %w", it.Err())
+			}
+			ts, v := it.At()
+			samples = append(samples, prompb.Sample{Timestamp: ts, Value: v})
 		}
-		samples = append(samples, s)
+		return samples, nil, it.Err()
 	}
+}
 
-	return samples, it.Err()
-}
\ No newline at end of file
+func toChunkEncoding(typ prompb.Chunk_Encoding) (chunkenc.Encoding, error) {
+	switch typ {
+	case prompb.Chunk_XOR:
+		return chunkenc.EncXOR, nil
+	case prompb.Chunk_HISTOGRAM:
+		return chunkenc.EncHistogram, nil
+	case prompb.Chunk_FLOAT_HISTOGRAM:
+		return chunkenc.EncFloatHistogram, nil
+	default:
+		return 0, fmt.Errorf("unsupported chunk encoding %v", typ)
+	}
+}
@@ -0,0 +1,18 @@
+package remoteread
+
+import "errors"
+
+// retryableError wraps an error that is worth retrying with backoff, e.g. a
+// network failure or a 5xx response, as opposed to a permanent one such as
+// a malformed query or a 4xx response.
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string { return e.err.Error() }
+func (e *retryableError) Unwrap() error { return e.err }
+
+func isRetryable(err error) bool {
+	var re *retryableError
+	return errors.As(err, &re)
+}
@@ -0,0 +1,66 @@
+package remoteread
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// resolveTenants builds the de-duplicated list of tenants Client.Read should
+// issue one read per, combining cfg.TenantsFile, cfg.Tenants and cfg.Tenant.
+// A single empty string is returned when no tenant is configured, meaning
+// "don't set a tenant header at all".
+func resolveTenants(cfg Config) ([]string, error) {
+	var tenants []string
+	if cfg.TenantsFile != "" {
+		fileTenants, err := readTenantsFile(cfg.TenantsFile)
+		if err != nil {
+			return nil, err
+		}
+		tenants = append(tenants, fileTenants...)
+	}
+	tenants = append(tenants, cfg.Tenants...)
+	if cfg.Tenant != "" {
+		tenants = append(tenants, cfg.Tenant)
+	}
+	if len(tenants) == 0 {
+		return []string{""}, nil
+	}
+	return dedupeStrings(tenants), nil
+}
+
+func readTenantsFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open tenants_file %q: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var tenants []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		tenant := strings.TrimSpace(sc.Text())
+		if tenant == "" || strings.HasPrefix(tenant, "#") {
+			continue
+		}
+		tenants = append(tenants, tenant)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read tenants_file %q: %w", path, err)
+	}
+	return tenants, nil
+}
+
+func dedupeStrings(s []string) []string {
+	seen := make(map[string]struct{}, len(s))
+	result := make([]string, 0, len(s))
+	for _, v := range s {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		result = append(result, v)
+	}
+	return result
+}
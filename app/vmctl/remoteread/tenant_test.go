@@ -0,0 +1,144 @@
+package remoteread
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/prometheus/prometheus/prompb"
+)
+
+func TestDedupeStrings(t *testing.T) {
+	f := func(in, want []string) {
+		t.Helper()
+		got := dedupeStrings(in)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("dedupeStrings(%v) = %v; want %v", in, got, want)
+		}
+	}
+
+	f(nil, []string{})
+	f([]string{"a"}, []string{"a"})
+	f([]string{"a", "b", "a", "c", "b"}, []string{"a", "b", "c"})
+}
+
+func TestResolveTenants(t *testing.T) {
+	dir := t.TempDir()
+	tenantsFile := filepath.Join(dir, "tenants.txt")
+	if err := os.WriteFile(tenantsFile, []byte("team-a\n# comment\n\nteam-b\n"), 0600); err != nil {
+		t.Fatalf("cannot write tenants file: %s", err)
+	}
+
+	f := func(cfg Config, want []string) {
+		t.Helper()
+		got, err := resolveTenants(cfg)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("resolveTenants(%+v) = %v; want %v", cfg, got, want)
+		}
+	}
+
+	f(Config{}, []string{""})
+	f(Config{Tenant: "team-a"}, []string{"team-a"})
+	f(Config{Tenants: []string{"team-a", "team-b", "team-a"}}, []string{"team-a", "team-b"})
+	f(Config{TenantsFile: tenantsFile, Tenant: "team-b"}, []string{"team-a", "team-b"})
+
+	if _, err := resolveTenants(Config{TenantsFile: filepath.Join(dir, "missing.txt")}); err == nil {
+		t.Error("expected an error for a missing tenants file, got nil")
+	}
+}
+
+// TestReadTagsSeriesPerTenantAndSendsTenantHeader asserts that Client.Read
+// issues one request per configured tenant on the configured tenant header,
+// and tags every series pulled for a non-default tenant with tenantLabel so
+// series from different tenants stay distinguishable once merged.
+func TestReadTagsSeriesPerTenantAndSendsTenantHeader(t *testing.T) {
+	var gotHeaders []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeaders = append(gotHeaders, r.Header.Get("X-Custom-Tenant"))
+		resp := &prompb.ReadResponse{
+			Results: []*prompb.QueryResult{{
+				Timeseries: []*prompb.TimeSeries{{
+					Labels:  []prompb.Label{{Name: "__name__", Value: "up"}},
+					Samples: []prompb.Sample{{Timestamp: 1, Value: 1}},
+				}},
+			}},
+		}
+		data, err := proto.Marshal(resp)
+		if err != nil {
+			t.Fatalf("cannot marshal response: %s", err)
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(data)
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Config{
+		Addr:              srv.URL,
+		Tenants:           []string{"team-a", "team-b"},
+		TenantHeader:      "X-Custom-Tenant",
+		DisableCheckpoint: true,
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error: %s", err)
+	}
+
+	var gotSeries []prompb.TimeSeries
+	filter := &Filter{Min: 0, Max: 1000, Selector: `{job="api"}`}
+	if err := c.Read(context.Background(), filter, func(ts prompb.TimeSeries) error {
+		gotSeries = append(gotSeries, ts)
+		return nil
+	}); err != nil {
+		t.Fatalf("Read() error: %s", err)
+	}
+
+	wantHeaders := []string{"team-a", "team-b"}
+	if !reflect.DeepEqual(gotHeaders, wantHeaders) {
+		t.Errorf("tenant header sent per request = %v; want %v", gotHeaders, wantHeaders)
+	}
+
+	if len(gotSeries) != 2 {
+		t.Fatalf("expected one series per tenant, got %d", len(gotSeries))
+	}
+	for i, wantTenant := range wantHeaders {
+		labels := gotSeries[i].Labels
+		last := labels[len(labels)-1]
+		if last.Name != defaultTenantLabel || last.Value != wantTenant {
+			t.Errorf("series %d labels = %v; want last label %s=%q", i, labels, defaultTenantLabel, wantTenant)
+		}
+	}
+}
+
+// TestPingSendsTenantHeader asserts Ping scopes its health check to the
+// first configured tenant via setTenantHeader, the same wiring fetch uses.
+func TestPingSendsTenantHeader(t *testing.T) {
+	var gotHeader string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Get("X-Scope-OrgID")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Config{
+		Addr:              srv.URL,
+		Tenants:           []string{"team-a"},
+		DisableCheckpoint: true,
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error: %s", err)
+	}
+
+	if err := c.Ping(); err != nil {
+		t.Fatalf("Ping() error: %s", err)
+	}
+	if gotHeader != "team-a" {
+		t.Errorf("tenant header sent to Ping() = %q; want %q", gotHeader, "team-a")
+	}
+}